@@ -0,0 +1,100 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import "context"
+
+const (
+	defaultActivitiesPerPage = 100
+	maxActivitiesPerPage     = 200
+)
+
+// ActivityResult is one item emitted by ActivitiesIter.
+type ActivityResult struct {
+	Activity Activity
+	Err      error
+}
+
+// ActivitiesIter streams an athlete's activities page by page, automatically
+// incrementing Page and using PerPage (default 100, cap 200) until an empty
+// page is returned or ctx is cancelled. The returned func stops the
+// iteration and closes the channel.
+func (s *strava) ActivitiesIter(ctx context.Context, req ActivitiesRequest) (<-chan ActivityResult, func()) {
+	out := make(chan ActivityResult)
+	ctx, cancel := context.WithCancel(ctx)
+
+	perPage := req.PerPage
+	if perPage == 0 {
+		perPage = defaultActivitiesPerPage
+	}
+	if perPage > maxActivitiesPerPage {
+		perPage = maxActivitiesPerPage
+	}
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			p := req
+			p.Page = page
+			p.PerPage = perPage
+
+			activities, err := s.Activities(p)
+			if err != nil {
+				select {
+				case out <- ActivityResult{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			if len(activities) == 0 {
+				return
+			}
+
+			for _, a := range activities {
+				select {
+				case out <- ActivityResult{Activity: a}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			page++
+		}
+	}()
+
+	return out, cancel
+}
+
+// ForEachActivity calls fn for every activity streamed by ActivitiesIter,
+// stopping at the first error returned by fn or by the iteration itself.
+func (s *strava) ForEachActivity(ctx context.Context, req ActivitiesRequest, fn func(Activity) error) error {
+	results, cancel := s.ActivitiesIter(ctx, req)
+	defer cancel()
+
+	for res := range results {
+		if res.Err != nil {
+			return res.Err
+		}
+
+		if err := fn(res.Activity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
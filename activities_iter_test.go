@@ -0,0 +1,113 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestActivitiesIter_PaginatesUntilEmpty(t *testing.T) {
+	pages := map[string][]Activity{
+		"1": {{ID: 1}, {ID: 2}},
+		"2": {{ID: 3}},
+		"3": {},
+	}
+
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		page := req.URL.Query().Get("page")
+		return jsonResponse(http.StatusOK, pages[page]), nil
+	}})
+
+	results, cancel := s.ActivitiesIter(context.Background(), ActivitiesRequest{})
+	defer cancel()
+
+	var got []uint64
+	for res := range results {
+		if res.Err != nil {
+			t.Fatalf("unexpected error: %v", res.Err)
+		}
+		got = append(got, res.Activity.ID)
+	}
+
+	want := []uint64{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestActivitiesIter_PropagatesError(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		return nil, wantErr
+	}})
+
+	results, cancel := s.ActivitiesIter(context.Background(), ActivitiesRequest{})
+	defer cancel()
+
+	res, ok := <-results
+	if !ok {
+		t.Fatal("channel closed without emitting the error")
+	}
+	if res.Err == nil {
+		t.Fatal("Err = nil, want the propagated transport error")
+	}
+
+	if _, ok := <-results; ok {
+		t.Fatal("channel should close after emitting the error")
+	}
+}
+
+func TestActivitiesIter_StopsOnCancel(t *testing.T) {
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusOK, []Activity{{ID: 1}}), nil
+	}})
+
+	results, cancel := s.ActivitiesIter(context.Background(), ActivitiesRequest{})
+
+	if res, ok := <-results; !ok || res.Activity.ID != 1 {
+		t.Fatalf("first result = %+v, ok=%v", res, ok)
+	}
+
+	cancel()
+
+	for range results {
+		// Drain until the producer goroutine observes the cancellation and closes the channel.
+	}
+}
+
+func TestForEachActivity_StopsAtFirstCallbackError(t *testing.T) {
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		page := req.URL.Query().Get("page")
+		if page == "1" {
+			return jsonResponse(http.StatusOK, []Activity{{ID: 1}, {ID: 2}}), nil
+		}
+		return jsonResponse(http.StatusOK, []Activity{}), nil
+	}})
+
+	wantErr := errors.New("stop here")
+	var seen []uint64
+
+	err := s.ForEachActivity(context.Background(), ActivitiesRequest{}, func(a Activity) error {
+		seen = append(seen, a.ID)
+		return wantErr
+	})
+
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if len(seen) != 1 {
+		t.Fatalf("seen = %v, want exactly one activity before stopping", seen)
+	}
+}
@@ -155,3 +155,136 @@ func (s *strava) Activities(p ActivitiesRequest) (out []Activity, err error) {
 	}
 	return
 }
+
+// CreateActivityRequest contains parameters to create a manual activity.
+type CreateActivityRequest struct {
+	Name           string    `json:"name"`
+	SportType      SportType `json:"sport_type"`
+	StartDateLocal time.Time `json:"start_date_local"`
+	ElapsedTime    uint64    `json:"elapsed_time"`
+	Distance       float64   `json:"distance"`
+	Description    string    `json:"description"`
+	Trainer        bool      `json:"trainer"`
+	Commute        bool      `json:"commute"`
+}
+
+// Values convert the request to the JSON body sent to Strava.
+func (p CreateActivityRequest) Values() types.Map {
+	m := types.Map{
+		"name":             p.Name,
+		"sport_type":       p.SportType,
+		"start_date_local": p.StartDateLocal.Format(time.RFC3339),
+		"elapsed_time":     p.ElapsedTime,
+	}
+
+	if p.Distance > 0 {
+		m.Set("distance", p.Distance)
+	}
+
+	if p.Description != "" {
+		m.Set("description", p.Description)
+	}
+
+	if p.Trainer {
+		m.Set("trainer", p.Trainer)
+	}
+
+	if p.Commute {
+		m.Set("commute", p.Commute)
+	}
+
+	return m
+}
+
+// CreateActivity creates a manual activity.
+func (s *strava) CreateActivity(p CreateActivityRequest) (out Activity, err error) {
+	r, err := s.POST("/activities", RequestParams{
+		WithBearer: true,
+		Values:     p.Values(),
+	})
+
+	defer closeHTTPResponse(r)
+	if err != nil {
+		return
+	}
+
+	if r.StatusCode == http.StatusCreated {
+		b, _ := ioutil.ReadAll(r.Body)
+		err = json.Unmarshal(b, &out)
+	}
+	return
+}
+
+// UpdateActivityRequest contains parameters to update an existing activity.
+type UpdateActivityRequest struct {
+	Commute      bool      `json:"commute"`
+	Trainer      bool      `json:"trainer"`
+	HideFromHome bool      `json:"hide_from_home"`
+	Description  string    `json:"description"`
+	Name         string    `json:"name"`
+	SportType    SportType `json:"sport_type"`
+	GearID       string    `json:"gear_id"`
+}
+
+// Values convert the request to the JSON body sent to Strava.
+func (p UpdateActivityRequest) Values() types.Map {
+	m := types.Map{
+		"commute":        p.Commute,
+		"trainer":        p.Trainer,
+		"hide_from_home": p.HideFromHome,
+	}
+
+	if p.Name != "" {
+		m.Set("name", p.Name)
+	}
+
+	if p.Description != "" {
+		m.Set("description", p.Description)
+	}
+
+	if p.SportType != "" {
+		m.Set("sport_type", p.SportType)
+	}
+
+	if p.GearID != "" {
+		m.Set("gear_id", p.GearID)
+	}
+
+	return m
+}
+
+// UpdateActivity updates the activity with the given id.
+func (s *strava) UpdateActivity(id uint64, p UpdateActivityRequest) (out Activity, err error) {
+	r, err := s.PUT(fmt.Sprintf("/activities/%d", id), RequestParams{
+		WithBearer: true,
+		Values:     p.Values(),
+	})
+
+	defer closeHTTPResponse(r)
+	if err != nil {
+		return
+	}
+
+	if r.StatusCode == http.StatusOK {
+		b, _ := ioutil.ReadAll(r.Body)
+		err = json.Unmarshal(b, &out)
+	}
+	return
+}
+
+// DeleteActivity deletes the activity with the given id.
+func (s *strava) DeleteActivity(id uint64) (err error) {
+	r, err := s.DELETE(fmt.Sprintf("/activities/%d", id), RequestParams{
+		WithBearer: true,
+	})
+
+	defer closeHTTPResponse(r)
+	if err != nil {
+		return
+	}
+
+	if r.StatusCode != http.StatusNoContent {
+		err = fmt.Errorf("unexpected status code %d", r.StatusCode)
+	}
+	return
+}
@@ -0,0 +1,115 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCreateActivityRequest_Values(t *testing.T) {
+	req := CreateActivityRequest{
+		Name:           "Morning Run",
+		SportType:      SportTypeRun,
+		StartDateLocal: time.Date(2020, 1, 2, 7, 0, 0, 0, time.UTC),
+		ElapsedTime:    1800,
+	}
+
+	values := req.Values()
+	if values["name"] != "Morning Run" || values["sport_type"] != SportTypeRun {
+		t.Fatalf("Values() = %+v, missing expected fields", values)
+	}
+	if _, ok := values["description"]; ok {
+		t.Errorf("Values() set description for an empty field, want it omitted")
+	}
+	if _, ok := values["distance"]; ok {
+		t.Errorf("Values() set distance for a zero field, want it omitted")
+	}
+}
+
+func TestCreateActivity(t *testing.T) {
+	var gotBody map[string]interface{}
+
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.Path != "/activities" {
+			t.Fatalf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		b, _ := ioutil.ReadAll(req.Body)
+		_ = json.Unmarshal(b, &gotBody)
+		return jsonResponse(http.StatusCreated, Activity{ID: 1, Name: "Morning Run"}), nil
+	}})
+
+	out, err := s.CreateActivity(CreateActivityRequest{
+		Name:           "Morning Run",
+		SportType:      SportTypeRun,
+		StartDateLocal: time.Now(),
+		ElapsedTime:    600,
+	})
+	if err != nil {
+		t.Fatalf("CreateActivity() error = %v", err)
+	}
+	if out.ID != 1 {
+		t.Errorf("ID = %d, want 1", out.ID)
+	}
+	if gotBody["name"] != "Morning Run" {
+		t.Errorf("request body name = %v, want %q", gotBody["name"], "Morning Run")
+	}
+}
+
+func TestUpdateActivity(t *testing.T) {
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPut || req.URL.Path != "/activities/42" {
+			t.Fatalf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		return jsonResponse(http.StatusOK, Activity{ID: 42, Name: "Renamed"}), nil
+	}})
+
+	out, err := s.UpdateActivity(42, UpdateActivityRequest{Name: "Renamed"})
+	if err != nil {
+		t.Fatalf("UpdateActivity() error = %v", err)
+	}
+	if out.Name != "Renamed" {
+		t.Errorf("Name = %q, want %q", out.Name, "Renamed")
+	}
+}
+
+func TestDeleteActivity(t *testing.T) {
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodDelete || req.URL.Path != "/activities/42" {
+			t.Fatalf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		return emptyResponse(http.StatusNoContent), nil
+	}})
+
+	if err := s.DeleteActivity(42); err != nil {
+		t.Fatalf("DeleteActivity() error = %v", err)
+	}
+}
+
+func TestDeleteActivity_UnexpectedStatus(t *testing.T) {
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		return emptyResponse(http.StatusOK), nil
+	}})
+
+	if err := s.DeleteActivity(42); err == nil {
+		t.Fatal("DeleteActivity() error = nil, want an error for an unexpected status code")
+	}
+}
+
+func TestActivitiesRequest_Queries(t *testing.T) {
+	req := ActivitiesRequest{Page: 2, PerPage: 50}
+
+	q := req.Queries()
+	if q["page"] != uint64(2) || q["per_page"] != uint64(50) {
+		t.Fatalf("Queries() = %+v, want page=2 and per_page=50", q)
+	}
+	if _, ok := q["after"]; ok {
+		t.Errorf("Queries() set after for a zero date, want it omitted")
+	}
+}
@@ -25,4 +25,13 @@ type Config struct {
 	RedirectURI  string `json:"redirect_uri" env:"STRAVA_REDIRECT_URI"`
 	Timeout      uint16 `json:"timeout" env:"STRAVA_TIMEOUT"`
 	Scope        string `json:"scope" env:"STRAVA_SCOPE"`
+
+	// RefreshSkew is the number of seconds before the actual expiration
+	// at which the access token is considered expired and refreshed.
+	// Defaults to 60 when left at zero.
+	RefreshSkew int64 `json:"refresh_skew" env:"STRAVA_REFRESH_SKEW"`
+
+	// RateLimit configures how the client reacts when usage nears Strava's
+	// rate limits. The zero value blocks until the next window.
+	RateLimit RateLimitConfig `json:"-"`
 }
@@ -0,0 +1,75 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// FieldError describes one invalid field reported by the Strava API.
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+}
+
+// StravaError is the structured error body returned by the Strava API for
+// non-2xx responses, in the form {"message": "...", "errors": [...]}.
+type StravaError struct {
+	Status  int          `json:"-"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors"`
+}
+
+// Error implements the error interface.
+func (e *StravaError) Error() string {
+	return fmt.Sprintf("strava: %s (status %d)", e.Message, e.Status)
+}
+
+// Is reports whether target is AuthorizationError, so that existing callers
+// using errors.Is(err, AuthorizationError) keep working for 401 responses.
+func (e *StravaError) Is(target error) bool {
+	return target == AuthorizationError && e.Status == http.StatusUnauthorized
+}
+
+// decodeStravaError reads and decodes a non-2xx response body into a StravaError.
+func decodeStravaError(r *http.Response) *StravaError {
+	se := &StravaError{Status: r.StatusCode}
+
+	b, _ := ioutil.ReadAll(r.Body)
+	_ = json.Unmarshal(b, se)
+
+	return se
+}
+
+// statusOf extracts the HTTP status carried by a StravaError, or 0 when err
+// doesn't wrap one.
+func statusOf(err error) int {
+	var se *StravaError
+	if errors.As(err, &se) {
+		return se.Status
+	}
+	return 0
+}
+
+// IsRateLimited reports whether err is a StravaError for a 429 response.
+func IsRateLimited(err error) bool {
+	return statusOf(err) == http.StatusTooManyRequests
+}
+
+// IsNotFound reports whether err is a StravaError for a 404 response.
+func IsNotFound(err error) bool {
+	return statusOf(err) == http.StatusNotFound
+}
+
+// IsAuthorization reports whether err is a StravaError for a 401 response.
+func IsAuthorization(err error) bool {
+	return statusOf(err) == http.StatusUnauthorized
+}
@@ -0,0 +1,84 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestStravaError_Error(t *testing.T) {
+	e := &StravaError{Status: http.StatusNotFound, Message: "Record Not Found"}
+
+	want := "strava: Record Not Found (status 404)"
+	if got := e.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestStravaError_Is(t *testing.T) {
+	e := &StravaError{Status: http.StatusUnauthorized}
+
+	if !errors.Is(e, AuthorizationError) {
+		t.Error("errors.Is(e, AuthorizationError) = false, want true for a 401 StravaError")
+	}
+
+	e.Status = http.StatusForbidden
+	if errors.Is(e, AuthorizationError) {
+		t.Error("errors.Is(e, AuthorizationError) = true, want false for a 403 StravaError")
+	}
+}
+
+func TestDecodeStravaError(t *testing.T) {
+	body := `{"message":"Bad Request","errors":[{"resource":"Activity","field":"type","code":"invalid"}]}`
+	r := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}
+
+	e := decodeStravaError(r)
+	if e.Status != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", e.Status, http.StatusBadRequest)
+	}
+	if e.Message != "Bad Request" {
+		t.Errorf("Message = %q, want %q", e.Message, "Bad Request")
+	}
+	if len(e.Errors) != 1 || e.Errors[0].Field != "type" {
+		t.Errorf("Errors = %+v, want one FieldError for field %q", e.Errors, "type")
+	}
+}
+
+func TestIsRateLimitedIsNotFoundIsAuthorization(t *testing.T) {
+	cases := []struct {
+		name          string
+		err           error
+		rateLimited   bool
+		notFound      bool
+		authorization bool
+	}{
+		{"rate limited", &StravaError{Status: http.StatusTooManyRequests}, true, false, false},
+		{"not found", &StravaError{Status: http.StatusNotFound}, false, true, false},
+		{"authorization", &StravaError{Status: http.StatusUnauthorized}, false, false, true},
+		{"plain error", errors.New("boom"), false, false, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IsRateLimited(c.err); got != c.rateLimited {
+				t.Errorf("IsRateLimited() = %v, want %v", got, c.rateLimited)
+			}
+			if got := IsNotFound(c.err); got != c.notFound {
+				t.Errorf("IsNotFound() = %v, want %v", got, c.notFound)
+			}
+			if got := IsAuthorization(c.err); got != c.authorization {
+				t.Errorf("IsAuthorization() = %v, want %v", got, c.authorization)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/kovacou/go-strava/polyline"
+)
+
+// DecodePolyline decodes the activity's full-resolution Map.Polyline.
+func (a Activity) DecodePolyline() ([]polyline.LatLng, error) {
+	return polyline.Decode(a.Map.Polyline)
+}
+
+// DecodeSummaryPolyline decodes the activity's summary Map.SummaryPolyline.
+func (a Activity) DecodeSummaryPolyline() ([]polyline.LatLng, error) {
+	return polyline.Decode(a.Map.SummaryPolyline)
+}
+
+// decodePolyline decodes Map.Polyline, falling back to Map.SummaryPolyline
+// when the full-resolution polyline isn't available.
+func (a Activity) decodePolyline() ([]polyline.LatLng, error) {
+	if a.Map.Polyline != "" {
+		return a.DecodePolyline()
+	}
+	return a.DecodeSummaryPolyline()
+}
+
+// geoJSONFeature is a minimal GeoJSON Feature.
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// geoJSONGeometry is a GeoJSON LineString geometry.
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
+// ToGeoJSON exports the activity's track as a GeoJSON Feature with a
+// LineString geometry and the activity metadata as properties.
+func (a Activity) ToGeoJSON() ([]byte, error) {
+	points, err := a.decodePolyline()
+	if err != nil {
+		return nil, err
+	}
+
+	coordinates := make([][]float64, len(points))
+	for i, p := range points {
+		coordinates[i] = []float64{p.Lng, p.Lat}
+	}
+
+	return json.Marshal(geoJSONFeature{
+		Type: "Feature",
+		Geometry: geoJSONGeometry{
+			Type:        "LineString",
+			Coordinates: coordinates,
+		},
+		Properties: map[string]interface{}{
+			"id":           a.ID,
+			"name":         a.Name,
+			"type":         a.Type,
+			"distance":     a.Distance,
+			"moving_time":  a.MovingTime,
+			"elapsed_time": a.ElapsedTime,
+			"start_date":   a.StartAt,
+		},
+	})
+}
+
+// gpxDoc is the root element of a GPX document.
+type gpxDoc struct {
+	XMLName xml.Name `xml:"gpx"`
+	Version string   `xml:"version,attr"`
+	Creator string   `xml:"creator,attr"`
+	Track   gpxTrack `xml:"trk"`
+}
+
+// gpxTrack is a GPX <trk> element.
+type gpxTrack struct {
+	Name    string     `xml:"name"`
+	Segment gpxSegment `xml:"trkseg"`
+}
+
+// gpxSegment is a GPX <trkseg> element.
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+// gpxPoint is a GPX <trkpt> element.
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lng  float64 `xml:"lon,attr"`
+	Time string  `xml:"time"`
+}
+
+// ToGPX writes the activity's track as a GPX <trk> with a single <trkseg>,
+// timestamping each point by interpolating between StartAt and StartAt+ElapsedTime.
+func (a Activity) ToGPX(w io.Writer) error {
+	points, err := a.decodePolyline()
+	if err != nil {
+		return err
+	}
+
+	doc := gpxDoc{
+		Version: "1.1",
+		Creator: "go-strava",
+		Track: gpxTrack{
+			Name:    a.Name,
+			Segment: gpxSegment{Points: make([]gpxPoint, len(points))},
+		},
+	}
+
+	duration := time.Duration(a.ElapsedTime) * time.Second
+	for i, p := range points {
+		t := a.StartAt
+		if n := len(points); n > 1 {
+			frac := float64(i) / float64(n-1)
+			t = a.StartAt.Add(time.Duration(frac * float64(duration)))
+		}
+
+		doc.Track.Segment.Points[i] = gpxPoint{
+			Lat:  p.Lat,
+			Lng:  p.Lng,
+			Time: t.UTC().Format(time.RFC3339),
+		}
+	}
+
+	if _, err = io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(doc)
+}
@@ -0,0 +1,79 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPolyline = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-5
+}
+
+func TestActivity_ToGeoJSON(t *testing.T) {
+	a := Activity{ID: 42, Name: "Morning Run", Type: "Run"}
+	a.Map.Polyline = testPolyline
+
+	b, err := a.ToGeoJSON()
+	if err != nil {
+		t.Fatalf("ToGeoJSON() error = %v", err)
+	}
+
+	var feature struct {
+		Type     string `json:"type"`
+		Geometry struct {
+			Type        string      `json:"type"`
+			Coordinates [][]float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+
+	if err := json.Unmarshal(b, &feature); err != nil {
+		t.Fatalf("unmarshal GeoJSON: %v", err)
+	}
+
+	if feature.Type != "Feature" || feature.Geometry.Type != "LineString" {
+		t.Fatalf("unexpected feature shape: %+v", feature)
+	}
+
+	if len(feature.Geometry.Coordinates) != 3 {
+		t.Fatalf("got %d coordinates, want 3", len(feature.Geometry.Coordinates))
+	}
+
+	// GeoJSON orders coordinates as [lng, lat].
+	first := feature.Geometry.Coordinates[0]
+	if !almostEqual(first[0], -120.2) || !almostEqual(first[1], 38.5) {
+		t.Errorf("first coordinate = %v, want [-120.2, 38.5]", first)
+	}
+
+	if feature.Properties["name"] != a.Name {
+		t.Errorf("properties[name] = %v, want %v", feature.Properties["name"], a.Name)
+	}
+}
+
+func TestActivity_ToGPX(t *testing.T) {
+	a := Activity{Name: "Morning Run", ElapsedTime: 120}
+	a.Map.Polyline = testPolyline
+	a.StartAt = time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	var buf strings.Builder
+	if err := a.ToGPX(&buf); err != nil {
+		t.Fatalf("ToGPX() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"<gpx", "<trk>", "<trkseg>", "<trkpt", "2020-01-01T08:00:00Z", "2020-01-01T08:02:00Z"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("GPX output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package polyline decodes Google's encoded polyline algorithm format (precision 5),
+// used by Strava to represent Activity.Map.Polyline and SummaryPolyline.
+package polyline
+
+import "errors"
+
+// precision is the number of decimal places encoded by Strava's polylines.
+const precision = 1e5
+
+// LatLng is a decoded coordinate pair.
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// Decode decodes an encoded polyline into a slice of coordinates.
+func Decode(encoded string) ([]LatLng, error) {
+	var (
+		points   []LatLng
+		lat, lng int
+		index    int
+		err      error
+	)
+
+	for index < len(encoded) {
+		lat, index, err = decodeValue(encoded, index, lat)
+		if err != nil {
+			return nil, err
+		}
+
+		lng, index, err = decodeValue(encoded, index, lng)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, LatLng{
+			Lat: float64(lat) / precision,
+			Lng: float64(lng) / precision,
+		})
+	}
+
+	return points, nil
+}
+
+// decodeValue decodes one varint-encoded, delta-coded coordinate component
+// starting at index, returning the new accumulated value and index.
+func decodeValue(encoded string, index, current int) (int, int, error) {
+	shift, result := uint(0), 0
+
+	for {
+		if index >= len(encoded) {
+			return 0, index, errors.New("polyline: truncated input")
+		}
+
+		b := int(encoded[index]) - 63
+		index++
+
+		result |= (b & 0x1f) << shift
+		shift += 5
+
+		if b < 0x20 {
+			break
+		}
+	}
+
+	delta := result >> 1
+	if result&1 != 0 {
+		delta = ^delta
+	}
+
+	return current + delta, index, nil
+}
@@ -0,0 +1,71 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package polyline
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-5
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+		want    []LatLng
+	}{
+		{
+			// From Google's encoded polyline algorithm documentation.
+			name:    "google doc example",
+			encoded: "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			want: []LatLng{
+				{Lat: 38.5, Lng: -120.2},
+				{Lat: 40.7, Lng: -120.95},
+				{Lat: 43.252, Lng: -126.453},
+			},
+		},
+		{
+			name:    "empty input",
+			encoded: "",
+			want:    nil,
+		},
+		{
+			name:    "single point at origin",
+			encoded: "??",
+			want: []LatLng{
+				{Lat: 0, Lng: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.encoded)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Decode() returned %d points, want %d", len(got), len(tt.want))
+			}
+
+			for i := range got {
+				if !almostEqual(got[i].Lat, tt.want[i].Lat) || !almostEqual(got[i].Lng, tt.want[i].Lng) {
+					t.Errorf("point %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecode_truncated(t *testing.T) {
+	if _, err := Decode("_"); err == nil {
+		t.Fatal("Decode() expected error for truncated input, got nil")
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned instead of sending a request when usage is at
+// the limit and RateLimit.Policy is RateLimitFail.
+var ErrRateLimited = errors.New("strava: rate limited")
+
+// RateLimitPolicy controls how the client reacts when usage nears Strava's
+// 100 requests/15min and 1000/day limits.
+type RateLimitPolicy int
+
+const (
+	// RateLimitBlock sleeps until the next 15-minute window before sending the request. It is the default.
+	RateLimitBlock RateLimitPolicy = iota
+
+	// RateLimitFail returns ErrRateLimited instead of sending the request.
+	RateLimitFail
+
+	// RateLimitCallback invokes Callback with the current usage and lets the request through.
+	RateLimitCallback
+)
+
+// RateLimitConfig configures rateLimitedTransport's behaviour when usage
+// nears Strava's limits.
+type RateLimitConfig struct {
+	Policy   RateLimitPolicy
+	Callback func(RateLimitStatus)
+}
+
+// RateLimitStatus is the rate limit usage reported by Strava's
+// X-RateLimit-Limit and X-RateLimit-Usage headers (15-minute window, then daily).
+type RateLimitStatus struct {
+	ShortLimit int
+	ShortUsage int
+	DailyLimit int
+	DailyUsage int
+}
+
+// RateLimit returns the most recently observed rate limit usage.
+func (s *strava) RateLimit() RateLimitStatus {
+	return s.transport.status()
+}
+
+// rateLimitedTransport wraps an http.RoundTripper, tracking Strava's
+// X-RateLimit-* headers and reacting to usage nearing the limit according
+// to a RateLimitConfig.
+type rateLimitedTransport struct {
+	base   http.RoundTripper
+	policy RateLimitConfig
+
+	mu    sync.Mutex
+	usage RateLimitStatus
+}
+
+// newRateLimitedTransport creates a rateLimitedTransport wrapping base.
+func newRateLimitedTransport(base http.RoundTripper, policy RateLimitConfig) *rateLimitedTransport {
+	return &rateLimitedTransport{base: base, policy: policy}
+}
+
+// status returns the most recently observed rate limit usage.
+func (t *rateLimitedTransport) status() RateLimitStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.throttle(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	t.recordUsage(resp.Header)
+
+	if resp.StatusCode == http.StatusTooManyRequests && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil) {
+		closeHTTPResponse(resp)
+		if err := waitForWindow(req.Context(), nextRateLimitWindow(time.Now())); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, berr := req.GetBody()
+			if berr != nil {
+				return nil, berr
+			}
+			req.Body = body
+		}
+
+		resp, err = t.base.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+
+		t.recordUsage(resp.Header)
+		return resp, nil
+	}
+
+	return resp, nil
+}
+
+// throttle applies the configured policy when usage is at either the short
+// (15-minute) or daily limit.
+func (t *rateLimitedTransport) throttle(ctx context.Context) error {
+	status := t.status()
+	atLimit := (status.ShortLimit > 0 && status.ShortUsage >= status.ShortLimit) ||
+		(status.DailyLimit > 0 && status.DailyUsage >= status.DailyLimit)
+	if !atLimit {
+		return nil
+	}
+
+	switch t.policy.Policy {
+	case RateLimitFail:
+		return ErrRateLimited
+
+	case RateLimitCallback:
+		if t.policy.Callback != nil {
+			t.policy.Callback(status)
+		}
+		return nil
+
+	default:
+		return waitForWindow(ctx, nextRateLimitWindow(time.Now()))
+	}
+}
+
+// waitForWindow blocks until t or ctx is done, whichever comes first. It's a
+// variable so tests can stub out the wait instead of sleeping for real.
+var waitForWindow = sleepUntil
+
+// sleepUntil blocks until t or ctx is done, whichever comes first.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordUsage parses the X-RateLimit-Limit and X-RateLimit-Usage headers.
+func (t *rateLimitedTransport) recordUsage(h http.Header) {
+	limit, usage := h.Get("X-RateLimit-Limit"), h.Get("X-RateLimit-Usage")
+	if limit == "" && usage == "" {
+		return
+	}
+
+	var status RateLimitStatus
+	status.ShortLimit, status.DailyLimit = parseRateLimitPair(limit)
+	status.ShortUsage, status.DailyUsage = parseRateLimitPair(usage)
+
+	t.mu.Lock()
+	t.usage = status
+	t.mu.Unlock()
+}
+
+// parseRateLimitPair parses a "short,daily" header value such as "100,1000".
+func parseRateLimitPair(v string) (short, daily int) {
+	parts := strings.SplitN(v, ",", 2)
+	if len(parts) > 0 {
+		short, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+	}
+	if len(parts) > 1 {
+		daily, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+	}
+	return
+}
+
+// nextRateLimitWindow returns the start of the next Strava 15-minute rate limit window after now.
+func nextRateLimitWindow(now time.Time) time.Time {
+	const window = 15 * time.Minute
+	return now.Truncate(window).Add(window)
+}
@@ -0,0 +1,141 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimitPair(t *testing.T) {
+	cases := []struct {
+		in         string
+		short, day int
+	}{
+		{"", 0, 0},
+		{"100", 100, 0},
+		{"100,1000", 100, 1000},
+		{" 100 , 1000 ", 100, 1000},
+	}
+
+	for _, c := range cases {
+		short, day := parseRateLimitPair(c.in)
+		if short != c.short || day != c.day {
+			t.Errorf("parseRateLimitPair(%q) = (%d, %d), want (%d, %d)", c.in, short, day, c.short, c.day)
+		}
+	}
+}
+
+func TestNextRateLimitWindow(t *testing.T) {
+	now := time.Date(2020, 1, 1, 10, 7, 30, 0, time.UTC)
+	want := time.Date(2020, 1, 1, 10, 15, 0, 0, time.UTC)
+
+	if got := nextRateLimitWindow(now); !got.Equal(want) {
+		t.Errorf("nextRateLimitWindow(%v) = %v, want %v", now, got, want)
+	}
+}
+
+func TestRateLimitedTransport_RecordUsage(t *testing.T) {
+	transport := newRateLimitedTransport(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		resp := emptyResponse(http.StatusOK)
+		resp.Header.Set("X-RateLimit-Limit", "100,1000")
+		resp.Header.Set("X-RateLimit-Usage", "3,40")
+		return resp, nil
+	}}, RateLimitConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	status := transport.status()
+	want := RateLimitStatus{ShortLimit: 100, ShortUsage: 3, DailyLimit: 1000, DailyUsage: 40}
+	if status != want {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+}
+
+func TestRateLimitedTransport_ThrottleFailPolicy(t *testing.T) {
+	transport := newRateLimitedTransport(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		t.Fatal("the base transport should not be reached once the limit is already hit")
+		return nil, nil
+	}}, RateLimitConfig{Policy: RateLimitFail})
+	transport.usage = RateLimitStatus{ShortLimit: 100, ShortUsage: 100}
+
+	_, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != ErrRateLimited {
+		t.Fatalf("err = %v, want %v", err, ErrRateLimited)
+	}
+}
+
+func TestRateLimitedTransport_ThrottleCallbackPolicy(t *testing.T) {
+	var got RateLimitStatus
+	transport := newRateLimitedTransport(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		return emptyResponse(http.StatusOK), nil
+	}}, RateLimitConfig{
+		Policy: RateLimitCallback,
+		Callback: func(s RateLimitStatus) {
+			got = s
+		},
+	})
+	transport.usage = RateLimitStatus{DailyLimit: 1000, DailyUsage: 1000}
+
+	if _, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if got.DailyUsage != 1000 {
+		t.Errorf("callback status = %+v, want DailyUsage 1000", got)
+	}
+}
+
+func TestRateLimitedTransport_RetriesOn429AndRecordsRetryUsage(t *testing.T) {
+	stubWaitForWindow(t)
+
+	var calls int
+	transport := newRateLimitedTransport(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return emptyResponse(http.StatusTooManyRequests), nil
+		}
+
+		resp := emptyResponse(http.StatusOK)
+		resp.Header.Set("X-RateLimit-Limit", "100,1000")
+		resp.Header.Set("X-RateLimit-Usage", "100,500")
+		return resp, nil
+	}}, RateLimitConfig{})
+
+	resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2 (initial 429 + retry)", calls)
+	}
+
+	if status := transport.status(); status.ShortUsage != 100 || status.DailyUsage != 500 {
+		t.Errorf("status = %+v, want the retry's usage to be recorded", status)
+	}
+}
+
+// stubWaitForWindow replaces waitForWindow with a no-op for the duration of
+// the test, so a 429 retry doesn't block until the real 15-minute window.
+func stubWaitForWindow(t *testing.T) {
+	t.Helper()
+
+	original := waitForWindow
+	waitForWindow = func(ctx context.Context, deadline time.Time) error {
+		return nil
+	}
+	t.Cleanup(func() {
+		waitForWindow = original
+	})
+}
@@ -0,0 +1,37 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+// SportType is the type of sport of an Activity, matching Strava's accepted set.
+type SportType string
+
+const (
+	SportTypeRun              SportType = "Run"
+	SportTypeTrailRun         SportType = "TrailRun"
+	SportTypeWalk             SportType = "Walk"
+	SportTypeHike             SportType = "Hike"
+	SportTypeRide             SportType = "Ride"
+	SportTypeMountainBikeRide SportType = "MountainBikeRide"
+	SportTypeGravelRide       SportType = "GravelRide"
+	SportTypeEBikeRide        SportType = "EBikeRide"
+	SportTypeVirtualRide      SportType = "VirtualRide"
+	SportTypeVirtualRun       SportType = "VirtualRun"
+	SportTypeSwim             SportType = "Swim"
+	SportTypeRowing           SportType = "Rowing"
+	SportTypeCanoeing         SportType = "Canoeing"
+	SportTypeKayaking         SportType = "Kayaking"
+	SportTypeSurfing          SportType = "Surfing"
+	SportTypeAlpineSki        SportType = "AlpineSki"
+	SportTypeBackcountrySki   SportType = "BackcountrySki"
+	SportTypeNordicSki        SportType = "NordicSki"
+	SportTypeSnowboard        SportType = "Snowboard"
+	SportTypeSkateboard       SportType = "Skateboard"
+	SportTypeWorkout          SportType = "Workout"
+	SportTypeCrossfit         SportType = "Crossfit"
+	SportTypeYoga             SportType = "Yoga"
+	SportTypeGolf             SportType = "Golf"
+	SportTypeSoccer           SportType = "Soccer"
+)
@@ -6,13 +6,17 @@
 package strava
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/kovacou/go-convert"
@@ -24,7 +28,12 @@ const (
 	GrantRefreshToken      = "refresh_token"
 )
 
-// AuthorizationError describe the token authorization error.
+// defaultRefreshSkew is the RefreshSkew applied when Config.RefreshSkew is left at zero.
+const defaultRefreshSkew = 60
+
+// AuthorizationError is the sentinel matched by errors.Is(err, AuthorizationError)
+// for a 401 response. Request now returns a *StravaError; use IsAuthorization
+// or errors.As for the full error body.
 var AuthorizationError = errors.New("authorization error")
 
 // Client is the client interface of Strava service.
@@ -41,19 +50,75 @@ type Client interface {
 	// Activities returns a list of activities.
 	Activities(ActivitiesRequest) ([]Activity, error)
 
-	// SetAccessToken set a new token.
+	// ActivitiesIter streams activities page by page until exhausted or ctx
+	// is cancelled. The returned func stops the iteration and closes the channel.
+	ActivitiesIter(ctx context.Context, req ActivitiesRequest) (<-chan ActivityResult, func())
+
+	// ForEachActivity calls fn for every activity streamed by ActivitiesIter,
+	// stopping at the first error returned by fn or by the iteration itself.
+	ForEachActivity(ctx context.Context, req ActivitiesRequest, fn func(Activity) error) error
+
+	// CreateActivity creates a manual activity.
+	CreateActivity(CreateActivityRequest) (Activity, error)
+
+	// UpdateActivity updates the activity with the given id.
+	UpdateActivity(id uint64, req UpdateActivityRequest) (Activity, error)
+
+	// DeleteActivity deletes the activity with the given id.
+	DeleteActivity(id uint64) error
+
+	// SetAccessToken set a new token. This remains a valid escape hatch for
+	// callers that manage token expiration themselves, but when a TokenStore
+	// and a refresh token are configured, the client refreshes on its own.
 	SetAccessToken(tok string)
 
+	// SetRefreshToken set the refresh token used to automatically renew the
+	// access token once it is close to expiration.
+	SetRefreshToken(tok string)
+
+	// SetTokenStore configures the TokenStore consulted before each
+	// authenticated request and updated whenever the token is refreshed.
+	SetTokenStore(store TokenStore)
+
 	// SetUserID set a new default user id for user's requests.
 	SetUserID(id uint64)
+
+	// CreateSubscription registers a push subscription delivering events to callbackURL.
+	CreateSubscription(callbackURL, verifyToken string) (WebhookSubscription, error)
+
+	// ListSubscriptions returns the existing push subscriptions.
+	ListSubscriptions() ([]WebhookSubscription, error)
+
+	// DeleteSubscription removes the push subscription with the given id.
+	DeleteSubscription(id uint64) error
+
+	// UploadActivity uploads an activity file for asynchronous processing.
+	UploadActivity(r io.Reader, params UploadParams) (Upload, error)
+
+	// UploadStatus returns the current status of the upload with the given id.
+	UploadStatus(id int64) (Upload, error)
+
+	// UploadAndWait uploads an activity file and blocks until it resolves to an Activity or fails.
+	UploadAndWait(ctx context.Context, r io.Reader, params UploadParams) (Upload, error)
+
+	// RateLimit returns the most recently observed rate limit usage.
+	RateLimit() RateLimitStatus
 }
 
 // RequestParams define the parameters to request the API.
 type RequestParams struct {
 	Queries            types.Map
 	Values             types.Map
+	Files              map[string]RequestFile
 	WithBearer         bool
 	WithFormURLEncoded bool
+	WithMultipart      bool
+}
+
+// RequestFile is a file attached to a multipart RequestParams.
+type RequestFile struct {
+	Name   string
+	Reader io.Reader
 }
 
 // AccessToken is the response of the Authorization.
@@ -85,11 +150,19 @@ func NewEnv() Client {
 
 // New create a new Strava client from the given config.
 func New(cfg Config) Client {
+	if cfg.RefreshSkew == 0 {
+		cfg.RefreshSkew = defaultRefreshSkew
+	}
+
+	transport := newRateLimitedTransport(http.DefaultTransport, cfg.RateLimit)
+
 	return &strava{
 		cfg: cfg,
 		Client: &http.Client{
-			Timeout: time.Duration(cfg.Timeout) * time.Second,
+			Timeout:   time.Duration(cfg.Timeout) * time.Second,
+			Transport: transport,
 		},
+		transport: transport,
 	}
 }
 
@@ -97,9 +170,12 @@ func New(cfg Config) Client {
 type strava struct {
 	*http.Client
 
-	cfg         Config
-	accessToken string
-	userID      uint64
+	cfg          Config
+	accessToken  string
+	refreshToken string
+	userID       uint64
+	tokenStore   TokenStore
+	transport    *rateLimitedTransport
 }
 
 // SetAccessToken set a new token.
@@ -107,6 +183,16 @@ func (s *strava) SetAccessToken(tok string) {
 	s.accessToken = tok
 }
 
+// SetRefreshToken set the refresh token used for automatic token renewal.
+func (s *strava) SetRefreshToken(tok string) {
+	s.refreshToken = tok
+}
+
+// SetTokenStore configures the TokenStore used to persist and look up access tokens.
+func (s *strava) SetTokenStore(store TokenStore) {
+	s.tokenStore = store
+}
+
 // SetUserID set a new user id.
 func (s *strava) SetUserID(id uint64) {
 	s.userID = id
@@ -151,10 +237,60 @@ func (s *strava) AuthorizationAccessToken(tok, grant string) (at AccessToken, er
 	return
 }
 
-// Request build a new request from the input and return the response.
-func (s *strava) Request(method, uri string, p RequestParams) (r *http.Response, err error) {
-	var values io.Reader
+// ensureValidToken refreshes the access token ahead of its expiration when
+// a TokenStore and a refresh token are configured, so callers don't have to
+// manage OAuth expiration themselves.
+func (s *strava) ensureValidToken() error {
+	if s.tokenStore == nil || s.refreshToken == "" {
+		return nil
+	}
+
+	at, err := s.tokenStore.Load()
+	if err != nil {
+		return err
+	}
+
+	// Adopt the stored token so a still-valid one survives process restarts
+	// without requiring a call to SetAccessToken.
+	if at.AccessToken != "" {
+		s.accessToken = at.AccessToken
+	}
+	if at.RefreshToken != "" {
+		s.refreshToken = at.RefreshToken
+	}
+
+	if at.ExpiresAt == 0 || time.Now().Unix()+s.cfg.RefreshSkew < at.ExpiresAt {
+		return nil
+	}
+
+	return s.refreshAccessToken()
+}
+
+// refreshAccessToken exchanges the refresh token for a new access token and
+// persists it through the configured TokenStore.
+func (s *strava) refreshAccessToken() error {
+	at, err := s.AuthorizationAccessToken(s.refreshToken, GrantRefreshToken)
+	if err != nil {
+		return err
+	}
 
+	s.accessToken = at.AccessToken
+	if at.RefreshToken != "" {
+		s.refreshToken = at.RefreshToken
+	}
+
+	if s.tokenStore != nil {
+		return s.tokenStore.Save(at)
+	}
+	return nil
+}
+
+// Request build a new request from the input and return the response. On an
+// AuthorizationError for a bearer-authenticated call, it refreshes the access
+// token and retries the same request once, replaying its body through
+// req.GetBody rather than rebuilding it from p (which would re-drain any
+// io.Reader supplied through RequestParams.Files).
+func (s *strava) Request(method, uri string, p RequestParams) (r *http.Response, err error) {
 	if p.Queries == nil {
 		p.Queries = types.Map{}
 	}
@@ -163,7 +299,15 @@ func (s *strava) Request(method, uri string, p RequestParams) (r *http.Response,
 		p.Values = types.Map{}
 	}
 
-	if method == http.MethodPost {
+	if p.WithBearer {
+		if err = s.ensureValidToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	values, contentType, err := requestBody(method, p)
+	if err != nil {
+		return nil, err
 	}
 
 	req, err := http.NewRequest(method, uri, values)
@@ -176,13 +320,6 @@ func (s *strava) Request(method, uri string, p RequestParams) (r *http.Response,
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
 	}
 
-	// Managing the content type to use : some endpoint need JSON and some need form encoded.
-	// To indicate Values must be encoded as FormURLEncoded, please pass WithFormURLEncoded with true.
-	contentType := "application/x-www-form-urlencoded"
-	if method == http.MethodPost && !p.WithFormURLEncoded {
-		contentType = "application/json"
-	}
-
 	// Encoding the queries and updating the raw query.
 	q := url.Values{}
 	for k, val := range p.Queries {
@@ -195,12 +332,99 @@ func (s *strava) Request(method, uri string, p RequestParams) (r *http.Response,
 	req.Header.Set("Accept", "application/json;charset=UTF-8")
 
 	r, err = s.Do(req)
-	if r.StatusCode == http.StatusUnauthorized {
-		err = AuthorizationError
+	if err != nil {
+		return
+	}
+
+	if r.StatusCode >= http.StatusBadRequest {
+		serr := decodeStravaError(r)
+		err = serr
+
+		if p.WithBearer && s.refreshToken != "" && serr.Status == http.StatusUnauthorized {
+			if rerr := s.refreshAccessToken(); rerr == nil {
+				closeHTTPResponse(r)
+				return s.retryRequest(req)
+			}
+		}
 	}
 	return
 }
 
+// retryRequest resends req once with the freshly refreshed bearer token. If
+// req had a body, it is replayed through req.GetBody instead of being
+// rebuilt, since the original io.Reader backing it may already be drained.
+func (s *strava) retryRequest(req *http.Request) (r *http.Response, err error) {
+	if req.GetBody != nil {
+		body, berr := req.GetBody()
+		if berr != nil {
+			return nil, berr
+		}
+		req.Body = body
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.accessToken))
+
+	r, err = s.Do(req)
+	if err != nil {
+		return
+	}
+
+	if r.StatusCode >= http.StatusBadRequest {
+		err = decodeStravaError(r)
+	}
+	return
+}
+
+// requestBody builds the request body and the Content-Type to use for the
+// given method and parameters : multipart form, URL-encoded form or JSON.
+func requestBody(method string, p RequestParams) (body io.Reader, contentType string, err error) {
+	switch {
+	case p.WithMultipart:
+		buf := &bytes.Buffer{}
+		w := multipart.NewWriter(buf)
+
+		for k, val := range p.Values {
+			if err = w.WriteField(k, convert.String(val)); err != nil {
+				return nil, "", err
+			}
+		}
+
+		for field, file := range p.Files {
+			fw, ferr := w.CreateFormFile(field, file.Name)
+			if ferr != nil {
+				return nil, "", ferr
+			}
+			if _, ferr = io.Copy(fw, file.Reader); ferr != nil {
+				return nil, "", ferr
+			}
+		}
+
+		if err = w.Close(); err != nil {
+			return nil, "", err
+		}
+
+		return buf, w.FormDataContentType(), nil
+
+	case method == http.MethodPost || method == http.MethodPut:
+		if p.WithFormURLEncoded {
+			form := url.Values{}
+			for k, val := range p.Values {
+				form.Set(k, convert.String(val))
+			}
+			return strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", nil
+		}
+
+		b, jerr := json.Marshal(p.Values)
+		if jerr != nil {
+			return nil, "", jerr
+		}
+		return bytes.NewReader(b), "application/json", nil
+
+	default:
+		return nil, "application/x-www-form-urlencoded", nil
+	}
+}
+
 // POST creates a new POST request.
 func (s *strava) POST(endpoint string, p RequestParams) (*http.Response, error) {
 	return s.Request(http.MethodPost, s.cfg.Host+endpoint, p)
@@ -210,3 +434,13 @@ func (s *strava) POST(endpoint string, p RequestParams) (*http.Response, error)
 func (s *strava) GET(endpoint string, p RequestParams) (*http.Response, error) {
 	return s.Request(http.MethodGet, s.cfg.Host+endpoint, p)
 }
+
+// DELETE creates a new DELETE request.
+func (s *strava) DELETE(endpoint string, p RequestParams) (*http.Response, error) {
+	return s.Request(http.MethodDelete, s.cfg.Host+endpoint, p)
+}
+
+// PUT creates a new PUT request.
+func (s *strava) PUT(endpoint string, p RequestParams) (*http.Response, error) {
+	return s.Request(http.MethodPut, s.cfg.Host+endpoint, p)
+}
@@ -0,0 +1,134 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEnsureValidToken_AdoptsStoredTokenWithoutRefreshing(t *testing.T) {
+	store := NewMemoryTokenStore()
+	_ = store.Save(AccessToken{
+		AccessToken:  "stored-access",
+		RefreshToken: "stored-refresh",
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+	})
+
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected HTTP call to %s; a still-valid stored token should not trigger a refresh", req.URL)
+		return nil, nil
+	}})
+	s.cfg.RefreshSkew = 60
+	s.tokenStore = store
+	s.refreshToken = "placeholder"
+
+	if err := s.ensureValidToken(); err != nil {
+		t.Fatalf("ensureValidToken() error = %v", err)
+	}
+
+	if s.accessToken != "stored-access" {
+		t.Errorf("accessToken = %q, want %q", s.accessToken, "stored-access")
+	}
+	if s.refreshToken != "stored-refresh" {
+		t.Errorf("refreshToken = %q, want %q", s.refreshToken, "stored-refresh")
+	}
+}
+
+func TestEnsureValidToken_RefreshesWhenNearExpiry(t *testing.T) {
+	store := NewMemoryTokenStore()
+	_ = store.Save(AccessToken{
+		AccessToken:  "stale-access",
+		RefreshToken: "stale-refresh",
+		ExpiresAt:    time.Now().Add(-time.Minute).Unix(),
+	})
+
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/api/v3/oauth/token" {
+			t.Fatalf("unexpected request to %s", req.URL)
+		}
+		return jsonResponse(http.StatusOK, AccessToken{
+			AccessToken:  "fresh-access",
+			RefreshToken: "fresh-refresh",
+			ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+		}), nil
+	}})
+	s.cfg.RefreshSkew = 60
+	s.tokenStore = store
+	s.refreshToken = "stale-refresh"
+
+	if err := s.ensureValidToken(); err != nil {
+		t.Fatalf("ensureValidToken() error = %v", err)
+	}
+
+	if s.accessToken != "fresh-access" {
+		t.Errorf("accessToken = %q, want %q", s.accessToken, "fresh-access")
+	}
+
+	saved, _ := store.Load()
+	if saved.AccessToken != "fresh-access" {
+		t.Errorf("store holds %q after refresh, want %q", saved.AccessToken, "fresh-access")
+	}
+}
+
+func TestEnsureValidToken_NoTokenStoreIsANoop(t *testing.T) {
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		t.Fatal("unexpected HTTP call with no TokenStore configured")
+		return nil, nil
+	}})
+	s.refreshToken = "some-refresh-token"
+
+	if err := s.ensureValidToken(); err != nil {
+		t.Fatalf("ensureValidToken() error = %v", err)
+	}
+}
+
+func TestRequest_RetriesOnceAfter401(t *testing.T) {
+	var calls int
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		calls++
+
+		if req.URL.Path == "/api/v3/oauth/token" {
+			return jsonResponse(http.StatusOK, AccessToken{AccessToken: "fresh-access"}), nil
+		}
+
+		if req.Header.Get("Authorization") == "Bearer fresh-access" {
+			return emptyResponse(http.StatusOK), nil
+		}
+		return emptyResponse(http.StatusUnauthorized), nil
+	}})
+	s.accessToken = "stale-access"
+	s.refreshToken = "refresh-me"
+
+	resp, err := s.Request(http.MethodGet, "https://api.example.com/activities", RequestParams{WithBearer: true})
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3 (initial 401, token refresh, retry)", calls)
+	}
+}
+
+func TestRequest_NoRetryWithoutRefreshToken(t *testing.T) {
+	var calls int
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		calls++
+		return emptyResponse(http.StatusUnauthorized), nil
+	}})
+	s.accessToken = "stale-access"
+
+	_, err := s.Request(http.MethodGet, "https://api.example.com/activities", RequestParams{WithBearer: true})
+	if !IsAuthorization(err) {
+		t.Fatalf("err = %v, want a 401 StravaError", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 (no retry without a refresh token)", calls)
+	}
+}
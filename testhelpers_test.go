@@ -0,0 +1,50 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// fakeRoundTripper lets tests stub HTTP responses without touching the network.
+type fakeRoundTripper struct {
+	fn func(*http.Request) (*http.Response, error)
+}
+
+func (f fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f.fn(req)
+}
+
+// jsonResponse builds an *http.Response with a JSON-encoded body.
+func jsonResponse(status int, v interface{}) *http.Response {
+	b, _ := json.Marshal(v)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json;charset=UTF-8"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(b)),
+	}
+}
+
+// emptyResponse builds a status-only *http.Response.
+func emptyResponse(status int) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+	}
+}
+
+// newTestStrava builds a *strava whose HTTP calls are served by rt, without
+// going through New (and its real rate-limited transport).
+func newTestStrava(rt http.RoundTripper) *strava {
+	return &strava{
+		cfg:    Config{Host: "https://api.example.com"},
+		Client: &http.Client{Transport: rt},
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// TokenStore is responsible for persisting and retrieving the AccessToken
+// used to authenticate requests, so callers don't have to track expiration
+// themselves.
+type TokenStore interface {
+	// Load returns the currently stored access token.
+	Load() (AccessToken, error)
+
+	// Save persists the given access token.
+	Save(AccessToken) error
+}
+
+// NewMemoryTokenStore creates a new in-memory TokenStore.
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{}
+}
+
+// memoryTokenStore is a TokenStore keeping the token in memory.
+type memoryTokenStore struct {
+	mu sync.RWMutex
+	at AccessToken
+}
+
+// Load returns the currently stored access token.
+func (s *memoryTokenStore) Load() (AccessToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.at, nil
+}
+
+// Save persists the given access token.
+func (s *memoryTokenStore) Save(at AccessToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.at = at
+	return nil
+}
+
+// NewFileTokenStore creates a new TokenStore persisting the access token
+// as JSON in the file located at path.
+func NewFileTokenStore(path string) TokenStore {
+	return &fileTokenStore{path: path}
+}
+
+// fileTokenStore is a TokenStore persisting the token as JSON on disk.
+type fileTokenStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// Load returns the currently stored access token.
+func (s *fileTokenStore) Load() (at AccessToken, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return at, nil
+	}
+	if err != nil {
+		return at, err
+	}
+
+	err = json.Unmarshal(b, &at)
+	return
+}
+
+// Save persists the given access token.
+func (s *fileTokenStore) Save(at AccessToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.Marshal(at)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.path, b, 0600)
+}
@@ -0,0 +1,49 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryTokenStore_SaveLoad(t *testing.T) {
+	store := NewMemoryTokenStore()
+
+	if at, err := store.Load(); err != nil || at.AccessToken != "" {
+		t.Fatalf("Load() = %+v, %v, want a zero-value AccessToken", at, err)
+	}
+
+	if err := store.Save(AccessToken{AccessToken: "abc"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	at, err := store.Load()
+	if err != nil || at.AccessToken != "abc" {
+		t.Fatalf("Load() = %+v, %v, want AccessToken %q", at, err, "abc")
+	}
+}
+
+func TestFileTokenStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	store := NewFileTokenStore(path)
+
+	if at, err := store.Load(); err != nil || at.AccessToken != "" {
+		t.Fatalf("Load() on a missing file = %+v, %v, want a zero-value AccessToken and no error", at, err)
+	}
+
+	if err := store.Save(AccessToken{AccessToken: "abc", RefreshToken: "def"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	at, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if at.AccessToken != "abc" || at.RefreshToken != "def" {
+		t.Errorf("Load() = %+v, want AccessToken %q and RefreshToken %q", at, "abc", "def")
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/kovacou/go-types"
+)
+
+// UploadParams are the parameters accepted by the uploads endpoint.
+type UploadParams struct {
+	// DataType is the format of the uploaded file : fit, fit.gz, tcx, tcx.gz, gpx or gpx.gz.
+	DataType    string
+	Name        string
+	Description string
+	ExternalID  string
+	Trainer     bool
+	Commute     bool
+}
+
+// Upload is the processing status of an uploaded activity file.
+type Upload struct {
+	ID         int64  `json:"id"`
+	ExternalID string `json:"external_id"`
+	Error      string `json:"error"`
+	Status     string `json:"status"`
+	ActivityID int64  `json:"activity_id"`
+}
+
+// Done reports whether the upload has resolved, either into an activity or an error.
+func (u Upload) Done() bool {
+	return u.ActivityID != 0 || u.Error != ""
+}
+
+// UploadActivity uploads an activity file for asynchronous processing and
+// returns its initial status. Use UploadStatus or UploadAndWait to wait for
+// the resulting Activity ID.
+func (s *strava) UploadActivity(r io.Reader, params UploadParams) (out Upload, err error) {
+	values := types.Map{
+		"data_type": params.DataType,
+		"trainer":   params.Trainer,
+		"commute":   params.Commute,
+	}
+
+	if params.Name != "" {
+		values.Set("name", params.Name)
+	}
+
+	if params.Description != "" {
+		values.Set("description", params.Description)
+	}
+
+	if params.ExternalID != "" {
+		values.Set("external_id", params.ExternalID)
+	}
+
+	resp, err := s.POST("/uploads", RequestParams{
+		WithBearer:    true,
+		WithMultipart: true,
+		Values:        values,
+		Files: map[string]RequestFile{
+			"file": {Name: "activity." + params.DataType, Reader: r},
+		},
+	})
+
+	defer closeHTTPResponse(resp)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode == http.StatusCreated {
+		b, _ := ioutil.ReadAll(resp.Body)
+		err = json.Unmarshal(b, &out)
+	}
+	return
+}
+
+// UploadStatus returns the current status of the upload with the given id.
+func (s *strava) UploadStatus(id int64) (out Upload, err error) {
+	resp, err := s.GET(fmt.Sprintf("/uploads/%d", id), RequestParams{
+		WithBearer: true,
+	})
+
+	defer closeHTTPResponse(resp)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		err = json.Unmarshal(b, &out)
+	}
+	return
+}
+
+// UploadAndWait uploads r and polls UploadStatus with a backoff until the
+// upload resolves to an Activity ID, fails, or ctx is cancelled.
+func (s *strava) UploadAndWait(ctx context.Context, r io.Reader, params UploadParams) (out Upload, err error) {
+	out, err = s.UploadActivity(r, params)
+	if err != nil {
+		return
+	}
+
+	backoff := time.Second
+	for !out.Done() {
+		select {
+		case <-ctx.Done():
+			return out, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		if out, err = s.UploadStatus(out.ID); err != nil {
+			return
+		}
+
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+
+	if out.Error != "" {
+		err = errors.New(out.Error)
+	}
+	return
+}
@@ -0,0 +1,154 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"context"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kovacou/go-types"
+)
+
+func TestRequestBody_Multipart(t *testing.T) {
+	p := RequestParams{
+		WithMultipart: true,
+		Values:        types.Map{"data_type": "gpx"},
+		Files: map[string]RequestFile{
+			"file": {Name: "activity.gpx", Reader: strings.NewReader("gpx-bytes")},
+		},
+	}
+
+	body, contentType, err := requestBody(http.MethodPost, p)
+	if err != nil {
+		t.Fatalf("requestBody() error = %v", err)
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("ParseMediaType() error = %v", err)
+	}
+
+	mr := multipart.NewReader(body, params["boundary"])
+	form, err := mr.ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("ReadForm() error = %v", err)
+	}
+
+	if got := form.Value["data_type"]; len(got) != 1 || got[0] != "gpx" {
+		t.Errorf("data_type field = %v, want [\"gpx\"]", got)
+	}
+
+	if len(form.File["file"]) != 1 {
+		t.Fatalf("file field = %v, want one file", form.File["file"])
+	}
+
+	fh := form.File["file"][0]
+	f, err := fh.Open()
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+
+	b, _ := ioutil.ReadAll(f)
+	if string(b) != "gpx-bytes" {
+		t.Errorf("file content = %q, want %q", b, "gpx-bytes")
+	}
+}
+
+func TestRequest_MultipartRetryReplaysBodyInstead(t *testing.T) {
+	var bodies []string
+
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/v3/oauth/token" {
+			return jsonResponse(http.StatusOK, AccessToken{AccessToken: "fresh"}), nil
+		}
+
+		b, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(b))
+
+		if req.Header.Get("Authorization") == "Bearer fresh" {
+			return emptyResponse(http.StatusCreated), nil
+		}
+		return emptyResponse(http.StatusUnauthorized), nil
+	}})
+	s.refreshToken = "refresh-me"
+
+	resp, err := s.Request(http.MethodPost, "https://api.example.com/uploads", RequestParams{
+		WithBearer:    true,
+		WithMultipart: true,
+		Values:        types.Map{"data_type": "gpx"},
+		Files: map[string]RequestFile{
+			"file": {Name: "a.gpx", Reader: strings.NewReader("file-bytes")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("upload endpoint called %d times, want 2 (initial 401 + retry)", len(bodies))
+	}
+
+	if !strings.Contains(bodies[0], "file-bytes") || !strings.Contains(bodies[1], "file-bytes") {
+		t.Fatalf("bodies = %q, want both attempts to carry the multipart file content", bodies)
+	}
+}
+
+func TestUploadAndWait_ResolvesImmediately(t *testing.T) {
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusCreated, Upload{ID: 1, ActivityID: 99}), nil
+	}})
+
+	out, err := s.UploadAndWait(context.Background(), strings.NewReader("data"), UploadParams{DataType: "gpx"})
+	if err != nil {
+		t.Fatalf("UploadAndWait() error = %v", err)
+	}
+	if out.ActivityID != 99 {
+		t.Errorf("ActivityID = %d, want 99", out.ActivityID)
+	}
+}
+
+func TestUploadAndWait_PollsUntilError(t *testing.T) {
+	var calls int
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return jsonResponse(http.StatusCreated, Upload{ID: 7}), nil
+		}
+		return jsonResponse(http.StatusOK, Upload{ID: 7, Error: "duplicate activity"}), nil
+	}})
+
+	_, err := s.UploadAndWait(context.Background(), strings.NewReader("data"), UploadParams{DataType: "gpx"})
+	if err == nil || !strings.Contains(err.Error(), "duplicate activity") {
+		t.Fatalf("err = %v, want it to mention %q", err, "duplicate activity")
+	}
+	if calls < 2 {
+		t.Fatalf("calls = %d, want at least 2 (initial upload + one status poll)", calls)
+	}
+}
+
+func TestUploadAndWait_ContextCancelled(t *testing.T) {
+	s := newTestStrava(fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusCreated, Upload{ID: 7}), nil
+	}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := s.UploadAndWait(ctx, strings.NewReader("data"), UploadParams{DataType: "gpx"})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/kovacou/go-types"
+)
+
+// WebhookSubscription is a representation of a Strava push subscription.
+type WebhookSubscription struct {
+	ID          uint64    `json:"id"`
+	CallbackURL string    `json:"callback_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookEvent is a push event delivered by Strava to a subscription's callback URL.
+type WebhookEvent struct {
+	ObjectType     string            `json:"object_type"`
+	ObjectID       uint64            `json:"object_id"`
+	AspectType     string            `json:"aspect_type"`
+	OwnerID        uint64            `json:"owner_id"`
+	SubscriptionID uint64            `json:"subscription_id"`
+	EventTime      int64             `json:"event_time"`
+	Updates        map[string]string `json:"updates"`
+}
+
+// WebhookSubscriber reacts to push events delivered through a WebhookHandler.
+type WebhookSubscriber interface {
+	// VerifyToken returns the token expected during the subscription handshake.
+	VerifyToken() string
+
+	// OnActivityCreate is called when an activity has been created.
+	OnActivityCreate(WebhookEvent)
+
+	// OnActivityUpdate is called when an activity has been updated.
+	OnActivityUpdate(WebhookEvent)
+
+	// OnActivityDelete is called when an activity has been deleted.
+	OnActivityDelete(WebhookEvent)
+
+	// OnAthleteDeauthorize is called when an athlete revokes access to the application.
+	OnAthleteDeauthorize(WebhookEvent)
+}
+
+// CreateSubscription registers a new push subscription delivering events to callbackURL.
+func (s *strava) CreateSubscription(callbackURL, verifyToken string) (out WebhookSubscription, err error) {
+	r, err := s.POST("/push_subscriptions", RequestParams{
+		WithFormURLEncoded: true,
+		Values: types.Map{
+			"client_id":     s.cfg.ClientID,
+			"client_secret": s.cfg.ClientSecret,
+			"callback_url":  callbackURL,
+			"verify_token":  verifyToken,
+		},
+	})
+
+	defer closeHTTPResponse(r)
+	if err != nil {
+		return
+	}
+
+	if r.StatusCode == http.StatusCreated {
+		b, _ := ioutil.ReadAll(r.Body)
+		err = json.Unmarshal(b, &out)
+	}
+	return
+}
+
+// ListSubscriptions returns the existing push subscriptions.
+func (s *strava) ListSubscriptions() (out []WebhookSubscription, err error) {
+	r, err := s.GET("/push_subscriptions", RequestParams{
+		Queries: types.Map{
+			"client_id":     s.cfg.ClientID,
+			"client_secret": s.cfg.ClientSecret,
+		},
+	})
+
+	defer closeHTTPResponse(r)
+	if err != nil {
+		return
+	}
+
+	if r.StatusCode == http.StatusOK {
+		b, _ := ioutil.ReadAll(r.Body)
+		err = json.Unmarshal(b, &out)
+	}
+	return
+}
+
+// DeleteSubscription removes the push subscription with the given id.
+func (s *strava) DeleteSubscription(id uint64) (err error) {
+	r, err := s.DELETE(fmt.Sprintf("/push_subscriptions/%d", id), RequestParams{
+		Queries: types.Map{
+			"client_id":     s.cfg.ClientID,
+			"client_secret": s.cfg.ClientSecret,
+		},
+	})
+
+	defer closeHTTPResponse(r)
+	if err != nil {
+		return
+	}
+
+	if r.StatusCode != http.StatusNoContent {
+		err = fmt.Errorf("unexpected status code %d", r.StatusCode)
+	}
+	return
+}
+
+// WebhookHandler returns an http.Handler serving both legs of Strava's push
+// subscription protocol: the GET verification handshake and the POST event
+// delivery, dispatching events to sub.
+func WebhookHandler(sub WebhookSubscriber) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			webhookVerify(w, r, sub)
+
+		case http.MethodPost:
+			webhookDispatch(w, r, sub)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// webhookVerify answers the GET handshake, echoing hub.challenge when hub.verify_token matches.
+func webhookVerify(w http.ResponseWriter, r *http.Request, sub WebhookSubscriber) {
+	q := r.URL.Query()
+	if q.Get("hub.verify_token") != sub.VerifyToken() {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	_ = json.NewEncoder(w).Encode(map[string]string{"hub.challenge": q.Get("hub.challenge")})
+}
+
+// webhookDispatch decodes the POST event payload and acks it immediately,
+// then routes it to the matching callback in the background. Strava expects
+// the ack within a couple of seconds and will retry delivery (and eventually
+// deactivate the subscription) if it has to wait on the callback.
+func webhookDispatch(w http.ResponseWriter, r *http.Request, sub WebhookSubscriber) {
+	defer r.Body.Close()
+
+	var event WebhookEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	go dispatchWebhookEvent(event, sub)
+}
+
+// dispatchWebhookEvent routes event to the matching WebhookSubscriber callback.
+func dispatchWebhookEvent(event WebhookEvent, sub WebhookSubscriber) {
+	switch {
+	case event.ObjectType == "athlete" && event.Updates["authorized"] == "false":
+		sub.OnAthleteDeauthorize(event)
+
+	case event.ObjectType == "activity" && event.AspectType == "create":
+		sub.OnActivityCreate(event)
+
+	case event.ObjectType == "activity" && event.AspectType == "update":
+		sub.OnActivityUpdate(event)
+
+	case event.ObjectType == "activity" && event.AspectType == "delete":
+		sub.OnActivityDelete(event)
+	}
+}
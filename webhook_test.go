@@ -0,0 +1,148 @@
+// Copyright © 2020 Alexandre KOVAC <contact@kovacou.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package strava
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSubscriber records the callbacks it receives and signals done once one fires.
+type fakeSubscriber struct {
+	mu     sync.Mutex
+	events []WebhookEvent
+	kinds  []string
+	done   chan struct{}
+}
+
+func newFakeSubscriber() *fakeSubscriber {
+	return &fakeSubscriber{done: make(chan struct{}, 1)}
+}
+
+func (f *fakeSubscriber) VerifyToken() string { return "expected-token" }
+
+func (f *fakeSubscriber) record(kind string, e WebhookEvent) {
+	f.mu.Lock()
+	f.events = append(f.events, e)
+	f.kinds = append(f.kinds, kind)
+	f.mu.Unlock()
+	f.done <- struct{}{}
+}
+
+func (f *fakeSubscriber) OnActivityCreate(e WebhookEvent)     { f.record("create", e) }
+func (f *fakeSubscriber) OnActivityUpdate(e WebhookEvent)     { f.record("update", e) }
+func (f *fakeSubscriber) OnActivityDelete(e WebhookEvent)     { f.record("delete", e) }
+func (f *fakeSubscriber) OnAthleteDeauthorize(e WebhookEvent) { f.record("deauthorize", e) }
+
+func TestWebhookHandler_VerifyHandshake(t *testing.T) {
+	sub := newFakeSubscriber()
+	h := WebhookHandler(sub)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhook?hub.verify_token=expected-token&hub.challenge=abc123", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var out map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out["hub.challenge"] != "abc123" {
+		t.Errorf("hub.challenge = %q, want %q", out["hub.challenge"], "abc123")
+	}
+}
+
+func TestWebhookHandler_VerifyHandshake_WrongToken(t *testing.T) {
+	sub := newFakeSubscriber()
+	h := WebhookHandler(sub)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhook?hub.verify_token=wrong&hub.challenge=abc123", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestWebhookHandler_DispatchAcksBeforeCallbackReturns(t *testing.T) {
+	sub := newFakeSubscriber()
+	h := WebhookHandler(sub)
+
+	event := WebhookEvent{ObjectType: "activity", AspectType: "create", ObjectID: 42}
+	b, _ := json.Marshal(event)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(b))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	// The callback runs in the background after the ack, so wait for it.
+	select {
+	case <-sub.done:
+	case <-time.After(time.Second):
+		t.Fatal("OnActivityCreate was not dispatched at all")
+	}
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if len(sub.events) != 1 || sub.events[0].ObjectID != 42 {
+		t.Errorf("events = %+v, want one event with ObjectID 42", sub.events)
+	}
+}
+
+func TestWebhookHandler_DispatchInvalidBody(t *testing.T) {
+	sub := newFakeSubscriber()
+	h := WebhookHandler(sub)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte("not-json")))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDispatchWebhookEvent_Routing(t *testing.T) {
+	cases := []struct {
+		name  string
+		event WebhookEvent
+		want  string
+	}{
+		{"create", WebhookEvent{ObjectType: "activity", AspectType: "create"}, "create"},
+		{"update", WebhookEvent{ObjectType: "activity", AspectType: "update"}, "update"},
+		{"delete", WebhookEvent{ObjectType: "activity", AspectType: "delete"}, "delete"},
+		{"deauthorize", WebhookEvent{ObjectType: "athlete", Updates: map[string]string{"authorized": "false"}}, "deauthorize"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sub := newFakeSubscriber()
+			dispatchWebhookEvent(c.event, sub)
+
+			sub.mu.Lock()
+			defer sub.mu.Unlock()
+			if len(sub.events) != 1 {
+				t.Fatalf("events = %+v, want exactly one dispatched event for %q", sub.events, c.want)
+			}
+			if sub.kinds[0] != c.want {
+				t.Errorf("dispatched to %q, want %q", sub.kinds[0], c.want)
+			}
+		})
+	}
+}